@@ -0,0 +1,227 @@
+package notify
+
+import (
+	"sync/atomic"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Event is implemented by ClosedEvent and ActionEvent, the two kinds
+// of occurrence the notifier's internal bus dispatches to Subscribers.
+type Event interface {
+	eventID() ID
+}
+
+// ClosedEvent reports that a notification closed.
+type ClosedEvent struct {
+	ID     ID
+	Reason CloseReason
+}
+
+func (e ClosedEvent) eventID() ID { return e.ID }
+
+// ActionEvent reports that a user invoked an action on a notification.
+type ActionEvent struct {
+	ID     ID
+	Action string
+}
+
+func (e ActionEvent) eventID() ID { return e.ID }
+
+// OnOverflow controls what a Subscription does when its channel is
+// full and another Event arrives for it.
+type OnOverflow int
+
+const (
+	// DropOldest discards the Subscription's oldest buffered Event to
+	// make room for the new one.
+	DropOldest OnOverflow = iota
+	// DropNewest discards the incoming Event, keeping the buffer as-is.
+	DropNewest
+	// Block makes the dispatcher wait for the Subscription to free up
+	// room. A single slow, Block-configured Subscriber stalls delivery
+	// to every other Subscriber, so use sparingly.
+	Block
+)
+
+// SubscribeOpts configures a Subscription created via notifier.Subscribe.
+type SubscribeOpts struct {
+	// IDs restricts delivery to Events about these notification IDs.
+	// Empty means every ID.
+	IDs []ID
+	// Actions restricts ActionEvent delivery to these action names;
+	// ClosedEvents are unaffected. Empty means every action.
+	Actions []string
+	// Buffer sizes the Subscription's channel. Defaults to
+	// channelBufferSize.
+	Buffer int
+	// OnOverflow is the backpressure policy applied once Buffer fills
+	// up. Defaults to DropOldest.
+	OnOverflow OnOverflow
+}
+
+// Subscription is a registration for Events on a notifier's bus,
+// created by notifier.Subscribe.
+type Subscription struct {
+	ch         chan Event
+	ids        map[ID]struct{}
+	actions    map[string]struct{}
+	onOverflow OnOverflow
+	notifier   *notifier
+}
+
+func newSubscription(n *notifier, opts SubscribeOpts) *Subscription {
+	buf := opts.Buffer
+	if buf <= 0 {
+		buf = channelBufferSize
+	}
+
+	sub := &Subscription{
+		ch:         make(chan Event, buf),
+		onOverflow: opts.OnOverflow,
+		notifier:   n,
+	}
+
+	if len(opts.IDs) > 0 {
+		sub.ids = make(map[ID]struct{}, len(opts.IDs))
+		for _, id := range opts.IDs {
+			sub.ids[id] = struct{}{}
+		}
+	}
+	if len(opts.Actions) > 0 {
+		sub.actions = make(map[string]struct{}, len(opts.Actions))
+		for _, a := range opts.Actions {
+			sub.actions[a] = struct{}{}
+		}
+	}
+
+	return sub
+}
+
+// Chan returns the channel Events are delivered on.
+func (s *Subscription) Chan() <-chan Event {
+	return s.ch
+}
+
+// Unsubscribe stops further delivery to this Subscription. It is safe
+// to call more than once.
+func (s *Subscription) Unsubscribe() {
+	s.notifier.unsubscribe(s)
+}
+
+func (s *Subscription) matches(ev Event) bool {
+	if s.ids != nil {
+		if _, ok := s.ids[ev.eventID()]; !ok {
+			return false
+		}
+	}
+	if s.actions != nil {
+		ae, ok := ev.(ActionEvent)
+		if !ok {
+			return true
+		}
+		if _, ok := s.actions[ae.Action]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// deliver applies s's overflow policy; it never blocks the dispatcher
+// unless s.onOverflow is Block.
+func (s *Subscription) deliver(ev Event) {
+	switch s.onOverflow {
+	case Block:
+		s.ch <- ev
+	case DropNewest:
+		select {
+		case s.ch <- ev:
+		default:
+			atomic.AddUint64(&s.notifier.stats.dropped, 1)
+		}
+	default: // DropOldest
+		select {
+		case s.ch <- ev:
+		default:
+			select {
+			case <-s.ch:
+			default:
+			}
+			select {
+			case s.ch <- ev:
+			default:
+			}
+			atomic.AddUint64(&s.notifier.stats.dropped, 1)
+		}
+	}
+}
+
+// Stats reports cumulative delivery counters for a notifier's bus.
+type Stats struct {
+	// Dispatched counts Events handed to the bus, once per Subscriber
+	// that existed at dispatch time, regardless of whether it matched.
+	Dispatched uint64
+	// Dropped counts Events a Subscriber's OnOverflow policy discarded.
+	Dropped uint64
+}
+
+// Subscribe registers a Subscription for bus Events per opts.
+func (n *notifier) Subscribe(opts SubscribeOpts) *Subscription {
+	sub := newSubscription(n, opts)
+
+	n.mu.Lock()
+	n.subs[sub] = struct{}{}
+	n.mu.Unlock()
+
+	return sub
+}
+
+func (n *notifier) unsubscribe(sub *Subscription) {
+	n.mu.Lock()
+	delete(n.subs, sub)
+	n.mu.Unlock()
+}
+
+// Stats returns a snapshot of the bus's delivery counters.
+func (n *notifier) Stats() Stats {
+	return Stats{
+		Dispatched: atomic.LoadUint64(&n.stats.dispatched),
+		Dropped:    atomic.LoadUint64(&n.stats.dropped),
+	}
+}
+
+// publish fans ev out to every current Subscriber, applying each
+// Subscriber's filter and overflow policy. It never blocks on a slow
+// Subscriber unless that Subscriber opted into Block.
+func (n *notifier) publish(ev Event) {
+	n.mu.Lock()
+	subs := make([]*Subscription, 0, len(n.subs))
+	for sub := range n.subs {
+		subs = append(subs, sub)
+	}
+	n.mu.Unlock()
+
+	atomic.AddUint64(&n.stats.dispatched, uint64(len(subs)))
+	for _, sub := range subs {
+		if sub.matches(ev) {
+			sub.deliver(ev)
+		}
+	}
+}
+
+func decodeSignal(signal *dbus.Signal) (Event, bool) {
+	switch signal.Name {
+	case signalNotificationClosed:
+		return ClosedEvent{
+			ID:     ID(signal.Body[0].(uint32)),
+			Reason: CloseReason(signal.Body[1].(uint32)),
+		}, true
+	case signalActionInvoked:
+		return ActionEvent{
+			ID:     ID(signal.Body[0].(uint32)),
+			Action: signal.Body[1].(string),
+		}, true
+	default:
+		return nil, false
+	}
+}
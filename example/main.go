@@ -3,99 +3,101 @@ package main
 import (
 	"fmt"
 	"log"
-	"os"
 	"sync"
+	"time"
 
 	"github.com/godbus/dbus/v5"
 	"github.com/jktr/go-notify"
 )
 
 func main() {
+
 	wg := &sync.WaitGroup{}
+	wg.Add(2)
 
 	conn, err := dbus.SessionBusPrivate()
 	if err != nil {
-		panic(err)
+		log.Fatal(err)
 	}
 	defer conn.Close()
 
 	if err = conn.Auth(nil); err != nil {
-		panic(err)
+		log.Fatal(err)
 	}
-
 	if err = conn.Hello(); err != nil {
-		panic(err)
+		log.Fatal(err)
 	}
-	// Basic usage
-	// Create a Notification to send
-	iconName := "mail-unread"
-	n := notify.Notification{
-		AppName:       "Test GO App",
-		ReplacesID:    uint32(0),
-		AppIcon:       iconName,
-		Summary:       "Test",
-		Body:          "This is a test of the DBus bindings for go.",
-		Actions:       []string{"cancel", "Cancel", "open", "Open"}, // tuples of (action_key, label)
-		Hints:         map[string]dbus.Variant{},
-		ExpireTimeout: int32(5000),
+
+	// prepare a Notification for sending ...
+	n := &notify.Notification{
+		AppName: "go-notify example app",
+		AppIcon: "mail-unread",
+		Summary: "go-notify example summary",
+		Body:    "This is the body of an extended go-notify example.",
+		Actions: []notify.NotificationAction{
+			{Name: "confirm", Summary: "Confirm."},
+			{Name: "cancel", Summary: "Cancel."},
+		},
+		Timeout: 5 * time.Second,
 	}
+	n.SetUrgency(notify.Critical)
 
-	// Ship it!
-	createdID, err := notify.SendNotification(conn, n)
+	// ... and then show it
+	createdID, err := notify.Send(conn, n)
 	if err != nil {
-		log.Printf("error sending notification: %v", err.Error())
+		log.Fatal("error sending notification:", err)
 	}
-	log.Printf("created notification with id: %v", createdID)
+	log.Printf("created notification with id: %d", createdID)
 
-	// List server features!
-	caps, err := notify.GetCapabilities(conn)
+	// list server features
+	caps, err := notify.GetServerCapabilities(conn)
 	if err != nil {
-		log.Printf("error fetching capabilities: %v", err)
+		log.Fatal("error fetching capabilities:", err)
 	}
 	for x := range caps {
-		fmt.Printf("Registered capability: %v\n", caps[x])
+		fmt.Printf("Registered capability: %s\n", caps[x])
 	}
 
-	info, err := notify.GetServerInformation(conn)
+	// list server vendor metadata
+	info, err := notify.GetServerInfo(conn)
 	if err != nil {
-		log.Printf("error getting server information: %v", err)
+		log.Fatal("error getting server information:", err)
 	}
 	fmt.Printf("Name:    %v\n", info.Name)
 	fmt.Printf("Vendor:  %v\n", info.Vendor)
 	fmt.Printf("Version: %v\n", info.Version)
 	fmt.Printf("Spec:    %v\n", info.SpecVersion)
 
-	// Listen for actions invoked!
-	onAction := func(action *notify.ActionInvokedSignal) {
-		log.Printf("ActionInvoked: %v Key: %v", action.ID, action.ActionKey)
-		wg.Done()
+	onAction := func(id notify.ID, action string) {
+		log.Printf("ActionInvoked: %d Key: %s", id, action)
 	}
-
-	onClosed := func(closer *notify.NotificationClosedSignal) {
-		log.Printf("NotificationClosed: %v Reason: %v", closer.ID, closer.Reason)
+	onClosed := func(id notify.ID, reason notify.CloseReason) {
+		log.Printf("NotificationClosed: %d Reason: %s", id, reason)
+		wg.Done()
 	}
 
 	// Notifier interface with event delivery
 	notifier, err := notify.New(
 		conn,
-		// action event handler
 		notify.WithOnAction(onAction),
-		// closed event handler
 		notify.WithOnClosed(onClosed),
 	)
+
 	if err != nil {
-		log.Fatalln(err.Error())
+		log.Fatal(err)
 	}
 	defer notifier.Close()
 
-	id, err := notifier.SendNotification(n)
+	h, err := notifier.Send(n)
 	if err != nil {
-		log.Printf("error sending notification: %v", err)
+		log.Fatalf("error sending notification: %v", err)
 	}
-	log.Printf("sent notification id: %v", id)
+	log.Printf("sent notification id: %v", h.ID())
 
-	//outClosed := notifier.NotificationClosed()
+	// closes of this notification go to its Handle, not to onClosed
+	h.OnClosed(func(reason notify.CloseReason) {
+		onClosed(h.ID(), reason)
+	})
 
-	wg.Add(2)
 	wg.Wait()
 }
@@ -0,0 +1,115 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrNotifierClosed is returned by Handle.Wait when the Notifier is
+// closed before the notification it refers to is closed.
+var ErrNotifierClosed = errors.New("notify: notifier closed")
+
+// Handle is returned by Notifier.Send and refers to a single sent
+// notification. Unlike the Notifier-wide WithOnAction/WithOnClosed
+// handlers, callbacks registered on a Handle only ever fire for this
+// notification's ID.
+//
+// A Handle is reaped from the Notifier's internal bookkeeping as soon
+// as its NotificationClosed signal arrives, so callbacks must be
+// registered before that happens.
+type Handle struct {
+	id       ID
+	notifier *notifier
+
+	mu          sync.Mutex
+	onAction    map[string]func()
+	onAnyAction func(actionName string)
+	onClosed    func(CloseReason)
+
+	closed chan struct{}
+	reason CloseReason
+}
+
+// ID returns the notification ID this Handle refers to.
+func (h *Handle) ID() ID {
+	return h.id
+}
+
+// Dismiss causes the notification to be forcefully closed.
+// See Notifier.Dismiss.
+func (h *Handle) Dismiss() error {
+	return h.notifier.Dismiss(h.id)
+}
+
+// OnAction registers fn to run when the user invokes the action
+// identified by name on this notification.
+func (h *Handle) OnAction(name string, fn func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.onAction == nil {
+		h.onAction = make(map[string]func())
+	}
+	h.onAction[name] = fn
+}
+
+// OnAnyAction registers fn to run when the user invokes any action on
+// this notification, regardless of whether a more specific OnAction
+// handler is also registered for it.
+func (h *Handle) OnAnyAction(fn func(actionName string)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onAnyAction = fn
+}
+
+// OnClosed registers fn to run when this notification closes.
+func (h *Handle) OnClosed(fn func(CloseReason)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onClosed = fn
+}
+
+// Wait blocks until the notification closes, ctx is done, or the
+// Notifier is closed, whichever happens first.
+func (h *Handle) Wait(ctx context.Context) (CloseReason, error) {
+	select {
+	case <-h.closed:
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		return h.reason, nil
+	case <-h.notifier.ctx.Done():
+		return 0, ErrNotifierClosed
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// closeWith records the close reason, releases any Wait callers and
+// runs the OnClosed callback, if any.
+func (h *Handle) closeWith(reason CloseReason) {
+	h.mu.Lock()
+	h.reason = reason
+	onClosed := h.onClosed
+	h.mu.Unlock()
+
+	close(h.closed)
+
+	if onClosed != nil {
+		go onClosed(reason)
+	}
+}
+
+// dispatchAction runs the callbacks registered for actionName, if any.
+func (h *Handle) dispatchAction(actionName string) {
+	h.mu.Lock()
+	fn := h.onAction[actionName]
+	onAny := h.onAnyAction
+	h.mu.Unlock()
+
+	if fn != nil {
+		go fn()
+	}
+	if onAny != nil {
+		go onAny(actionName)
+	}
+}
@@ -0,0 +1,139 @@
+package notify
+
+import "github.com/godbus/dbus/v5"
+
+// Category classifies a notification so the server can pick an
+// appropriate icon, sound, or placement for it.
+//
+// Spec: https://specifications.freedesktop.org/notification-spec/latest/ar01s06.html
+type Category string
+
+// Predefined categories from the spec's Table of Categories.
+// Categories outside that table should be built with X instead.
+const (
+	CategoryDeviceAdded         Category = "device.added"
+	CategoryDeviceError         Category = "device.error"
+	CategoryDeviceRemoved       Category = "device.removed"
+	CategoryEmailArrived        Category = "email.arrived"
+	CategoryEmailBounced        Category = "email.bounced"
+	CategoryIMError             Category = "im.error"
+	CategoryIMReceived          Category = "im.received"
+	CategoryNetworkConnected    Category = "network.connected"
+	CategoryNetworkDisconnected Category = "network.disconnected"
+	CategoryNetworkError        Category = "network.error"
+	CategoryPresenceOffline     Category = "presence.offline"
+	CategoryPresenceOnline      Category = "presence.online"
+	CategoryTransferComplete    Category = "transfer.complete"
+	CategoryTransferError       Category = "transfer.error"
+)
+
+// X builds a vendor-specific category, e.g. X("kde", "device.mount"),
+// as allowed by the spec for categories outside the predefined table.
+func X(vendor, kind string) Category {
+	return Category("x-" + vendor + "." + kind)
+}
+
+// hint keys, as named in the spec's Table of Hints.
+const (
+	hintActionIcons   = "action-icons"
+	hintCategory      = "category"
+	hintDesktopEntry  = "desktop-entry"
+	hintIconData      = "icon_data" // legacy key, superseded by the image-data hint
+	hintImagePath     = "image-path"
+	hintResident      = "resident"
+	hintSoundFile     = "sound-file"
+	hintSoundName     = "sound-name"
+	hintSuppressSound = "suppress-sound"
+	hintTransient     = "transient"
+	hintX             = "x"
+	hintY             = "y"
+)
+
+func (note *Notification) setHint(key string, val interface{}) *Notification {
+	if note.Hints == nil {
+		note.Hints = make(map[string]dbus.Variant)
+	}
+	note.Hints[key] = dbus.MakeVariant(val)
+	return note
+}
+
+// SetCategory sets the category hint, letting the server choose a
+// fitting icon, sound, or placement for the notification.
+func (note *Notification) SetCategory(category Category) *Notification {
+	return note.setHint(hintCategory, string(category))
+}
+
+// GetCategory returns the category hint, if one was set.
+func (note *Notification) GetCategory() (category Category, ok bool) {
+	v, ok := note.Hints[hintCategory]
+	if !ok {
+		return "", false
+	}
+	s, ok := v.Value().(string)
+	return Category(s), ok
+}
+
+// SetSoundName sets the themed sound, by name, to be played when the
+// notification is shown, following the XDG Sound Naming Specification.
+// It takes precedence over SetSoundFile.
+func (note *Notification) SetSoundName(name string) *Notification {
+	return note.setHint(hintSoundName, name)
+}
+
+// SetSoundFile sets a sound file, by path, to be played when the
+// notification is shown.
+func (note *Notification) SetSoundFile(path string) *Notification {
+	return note.setHint(hintSoundFile, path)
+}
+
+// SetSuppressSound suppresses the server's default sound, e.g. to play
+// a custom sound via SetSoundFile/SetSoundName instead.
+func (note *Notification) SetSuppressSound(suppress bool) *Notification {
+	return note.setHint(hintSuppressSound, suppress)
+}
+
+// SetTransient marks the notification as transient, asking the server
+// to skip persisting it in a notification history/log.
+func (note *Notification) SetTransient(transient bool) *Notification {
+	return note.setHint(hintTransient, transient)
+}
+
+// SetResident asks the server to keep the notification around after
+// the action invoked by the user is handled, instead of closing it.
+// Only meaningful for servers that support the "persistence" capability.
+func (note *Notification) SetResident(resident bool) *Notification {
+	return note.setHint(hintResident, resident)
+}
+
+// SetActionIcons asks the server to interpret Action names as icon
+// names, to be displayed instead of the action's text.
+func (note *Notification) SetActionIcons(iconic bool) *Notification {
+	return note.setHint(hintActionIcons, iconic)
+}
+
+// SetDesktopEntry sets the basename (without the .desktop extension) of
+// the application's .desktop file, letting the server look up the
+// application's name and icon.
+func (note *Notification) SetDesktopEntry(name string) *Notification {
+	return note.setHint(hintDesktopEntry, name)
+}
+
+// SetImagePath sets the icon to display, by path or themed icon name,
+// overriding AppIcon for this notification specifically.
+func (note *Notification) SetImagePath(path string) *Notification {
+	return note.setHint(hintImagePath, path)
+}
+
+// SetIconData sets raw icon pixel bytes under the legacy icon_data
+// hint. Prefer SetImageData, which uses the current image-data hint
+// and validates its fields against the spec.
+func (note *Notification) SetIconData(data []byte) *Notification {
+	return note.setHint(hintIconData, data)
+}
+
+// SetPosition suggests where on screen the server should place the
+// notification. Few servers honor this hint.
+func (note *Notification) SetPosition(x, y int32) *Notification {
+	note.setHint(hintX, x)
+	return note.setHint(hintY, y)
+}
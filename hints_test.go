@@ -0,0 +1,72 @@
+package notify
+
+import "testing"
+
+// TestHintSignatures asserts that each typed hint builder emits a
+// dbus.Variant whose signature matches the spec's Table of Hints, e.g.
+// category is a string ("s"), transient a boolean ("b"), x/y ints ("i").
+func TestHintSignatures(t *testing.T) {
+	cases := []struct {
+		key  string
+		want string
+	}{
+		{hintActionIcons, "b"},
+		{hintCategory, "s"},
+		{hintDesktopEntry, "s"},
+		{hintIconData, "ay"},
+		{hintImagePath, "s"},
+		{hintResident, "b"},
+		{hintSoundFile, "s"},
+		{hintSoundName, "s"},
+		{hintSuppressSound, "b"},
+		{hintTransient, "b"},
+		{hintX, "i"},
+		{hintY, "i"},
+	}
+
+	build := func() *Notification {
+		return (&Notification{}).
+			SetCategory(CategoryDeviceAdded).
+			SetSoundName("name").
+			SetSoundFile("path").
+			SetSuppressSound(true).
+			SetTransient(true).
+			SetResident(true).
+			SetActionIcons(true).
+			SetDesktopEntry("entry").
+			SetImagePath("path").
+			SetIconData([]byte{1, 2, 3}).
+			SetPosition(1, 2)
+	}
+	note := build()
+
+	for _, tc := range cases {
+		v, ok := note.Hints[tc.key]
+		if !ok {
+			t.Errorf("hint %q was not set", tc.key)
+			continue
+		}
+		if got := v.Signature().String(); got != tc.want {
+			t.Errorf("hint %q signature: got %q, want %q", tc.key, got, tc.want)
+		}
+	}
+}
+
+func TestCategoryX(t *testing.T) {
+	got := X("kde", "device.mount")
+	want := Category("x-kde.device.mount")
+	if got != want {
+		t.Errorf("X(\"kde\", \"device.mount\") = %q, want %q", got, want)
+	}
+}
+
+func TestGetCategory(t *testing.T) {
+	note := (&Notification{}).SetCategory(CategoryNetworkConnected)
+	if got, ok := note.GetCategory(); !ok || got != CategoryNetworkConnected {
+		t.Errorf("GetCategory() = %q, %v, want %q, true", got, ok, CategoryNetworkConnected)
+	}
+
+	if _, ok := (&Notification{}).GetCategory(); ok {
+		t.Error("GetCategory() on a Notification with no category hint should report ok=false")
+	}
+}
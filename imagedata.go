@@ -0,0 +1,140 @@
+package notify
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"strconv"
+	"strings"
+)
+
+// hint keys for raw pixel data, by the spec version that introduced
+// them. Spec 1.1 renamed "icon_data" to "image_data", and spec 1.2
+// renamed it again to "image-data"; servers following an older spec
+// only understand their contemporary key.
+const (
+	hintImageData       = "image-data" // spec >= 1.2
+	hintImageDataLegacy = "image_data" // spec == 1.1
+	// hintIconData ("icon_data", spec <= 1.0) is declared in hints.go
+)
+
+// ImageData carries raw pixel data for the image-data hint (and its
+// older image_data/icon_data aliases), letting clients ship an
+// in-memory icon -- e.g. album art fetched over HTTP -- instead of a
+// file path (see SetImagePath).
+//
+// Its field order matches the spec's struct signature (iiibiiay), so
+// it marshals to the correct dbus.Variant without further tagging.
+type ImageData struct {
+	Width         int32
+	Height        int32
+	Rowstride     int32
+	HasAlpha      bool
+	BitsPerSample int32
+	Channels      int32
+	Data          []byte
+}
+
+// Validate reports whether d is well-formed per the spec: Data must be
+// exactly Height*Rowstride bytes, Channels must be 3 (RGB) or 4 (RGBA),
+// and BitsPerSample must be 8, the only sample depth in practical use.
+func (d ImageData) Validate() error {
+	if d.BitsPerSample != 8 {
+		return fmt.Errorf("notify: ImageData.BitsPerSample must be 8, got %d", d.BitsPerSample)
+	}
+	if d.Channels != 3 && d.Channels != 4 {
+		return fmt.Errorf("notify: ImageData.Channels must be 3 or 4, got %d", d.Channels)
+	}
+	if want := int(d.Height) * int(d.Rowstride); len(d.Data) != want {
+		return fmt.Errorf("notify: ImageData.Data is %d bytes, want Height*Rowstride = %d", len(d.Data), want)
+	}
+	return nil
+}
+
+// ImageDataFromRGBA builds an ImageData from an RGBA image. The spec's
+// pixel format, inherited from GdkPixbuf, expects straight (not
+// premultiplied) alpha, so the image is converted accordingly.
+func ImageDataFromRGBA(img *image.RGBA) ImageData {
+	b := img.Bounds()
+	nrgba := image.NewNRGBA(b)
+	draw.Draw(nrgba, b, img, b.Min, draw.Src)
+	return ImageDataFromNRGBA(nrgba)
+}
+
+// ImageDataFromNRGBA builds an ImageData from a (straight alpha) NRGBA
+// image.
+func ImageDataFromNRGBA(img *image.NRGBA) ImageData {
+	b := img.Bounds()
+	return ImageData{
+		Width:         int32(b.Dx()),
+		Height:        int32(b.Dy()),
+		Rowstride:     int32(img.Stride),
+		HasAlpha:      true,
+		BitsPerSample: 8,
+		Channels:      4,
+		Data:          img.Pix,
+	}
+}
+
+// imageDataHintKey picks the hint key to emit raw pixel data under,
+// given the SpecVersion reported by GetServerInfo. An empty
+// specVersion targets the current spec.
+func imageDataHintKey(specVersion string) string {
+	switch {
+	case specVersion == "" || specAtLeast(specVersion, 1, 2):
+		return hintImageData
+	case specAtLeast(specVersion, 1, 1):
+		return hintImageDataLegacy
+	default:
+		return hintIconData
+	}
+}
+
+func specAtLeast(specVersion string, wantMajor, wantMinor int) bool {
+	major, minor, ok := parseSpecVersion(specVersion)
+	if !ok {
+		// unparseable version: assume current spec rather than
+		// degrading silently to a legacy hint key.
+		return true
+	}
+	if major != wantMajor {
+		return major > wantMajor
+	}
+	return minor >= wantMinor
+}
+
+func parseSpecVersion(specVersion string) (major, minor int, ok bool) {
+	parts := strings.SplitN(specVersion, ".", 2)
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	if len(parts) > 1 {
+		if minor, err = strconv.Atoi(parts[1]); err != nil {
+			return 0, 0, false
+		}
+	}
+	return major, minor, true
+}
+
+// SetImageData sets the notification's icon from raw pixel data.
+// specVersion should be the SpecVersion reported by GetServerInfo;
+// pass "" to target the current specification (>= 1.2).
+func (note *Notification) SetImageData(data ImageData, specVersion string) *Notification {
+	return note.setHint(imageDataHintKey(specVersion), data)
+}
+
+// SetImage is SetImageData targeting the current specification.
+// Use SetImageData directly if you need to support an older server.
+func (note *Notification) SetImage(data ImageData) *Notification {
+	return note.SetImageData(data, "")
+}
+
+// SetImageDataChecked is like SetImageData, but first validates data
+// and returns an error instead of sending a malformed hint.
+func (note *Notification) SetImageDataChecked(data ImageData, specVersion string) (*Notification, error) {
+	if err := data.Validate(); err != nil {
+		return note, err
+	}
+	return note.SetImageData(data, specVersion), nil
+}
@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// TestImageDataSignature asserts that ImageData's field order marshals
+// to the spec's image-data struct signature, (iiibiiay).
+func TestImageDataSignature(t *testing.T) {
+	data := ImageData{
+		Width:         1,
+		Height:        1,
+		Rowstride:     4,
+		HasAlpha:      true,
+		BitsPerSample: 8,
+		Channels:      4,
+		Data:          []byte{0, 0, 0, 0},
+	}
+
+	const want = "(iiibiiay)"
+	if got := dbus.MakeVariant(data).Signature().String(); got != want {
+		t.Errorf("ImageData signature: got %q, want %q", got, want)
+	}
+}
+
+func TestSetImageDataHintKey(t *testing.T) {
+	cases := []struct {
+		specVersion string
+		want        string
+	}{
+		{"", hintImageData},
+		{"1.2", hintImageData},
+		{"2.0", hintImageData},
+		{"1.1", hintImageDataLegacy},
+		{"1.0", hintIconData},
+		{"not-a-version", hintImageData},
+	}
+
+	data := ImageData{Width: 1, Height: 1, Rowstride: 1, BitsPerSample: 8, Channels: 3, Data: []byte{0}}
+
+	for _, tc := range cases {
+		note := (&Notification{}).SetImageData(data, tc.specVersion)
+		if _, ok := note.Hints[tc.want]; !ok {
+			t.Errorf("specVersion %q: expected hint %q to be set, got %v", tc.specVersion, tc.want, note.Hints)
+		}
+	}
+}
+
+func TestImageDataValidate(t *testing.T) {
+	valid := ImageData{Width: 1, Height: 2, Rowstride: 4, BitsPerSample: 8, Channels: 4, Data: make([]byte, 8)}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() on a well-formed ImageData: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		data ImageData
+	}{
+		{"bad bits per sample", ImageData{BitsPerSample: 16, Channels: 4, Height: 1, Rowstride: 1, Data: make([]byte, 1)}},
+		{"bad channel count", ImageData{BitsPerSample: 8, Channels: 2, Height: 1, Rowstride: 1, Data: make([]byte, 1)}},
+		{"short data", ImageData{BitsPerSample: 8, Channels: 4, Height: 2, Rowstride: 4, Data: make([]byte, 4)}},
+	}
+	for _, tc := range cases {
+		if err := tc.data.Validate(); err == nil {
+			t.Errorf("%s: expected Validate() to return an error", tc.name)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package notify
+
+import (
+	"errors"
+	"regexp"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const signalNameOwnerChanged = "org.freedesktop.DBus.NameOwnerChanged"
+
+// ErrCapabilityNegotiationDisabled is returned by SendNegotiated when
+// WithCapabilityNegotiation wasn't passed to New: without it, the
+// Notifier never subscribes to NameOwnerChanged, so a restarted server
+// would be judged against a capability cache that's stale forever.
+var ErrCapabilityNegotiationDisabled = errors.New("notify: SendNegotiated requires WithCapabilityNegotiation to have been passed to New")
+
+// markupTags matches the small set of body markup tags the spec
+// allows servers to support under the "body-markup" capability.
+// Anything else in Body is plain text, not markup, and is left alone.
+var markupTags = regexp.MustCompile(`(?i)</?(b|i|u|a|img)(\s[^>]*)?>`)
+
+func stripBodyMarkup(body string) string {
+	return markupTags.ReplaceAllString(body, "")
+}
+
+// capCache caches a Notifier's server capability list, invalidated on
+// NameOwnerChanged for org.freedesktop.Notifications so a restarted
+// server is re-queried instead of judged against stale capabilities.
+type capCache struct {
+	mu     sync.Mutex
+	caps   map[string]struct{}
+	cached bool
+}
+
+func (c *capCache) get(n *notifier) (map[string]struct{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cached {
+		return c.caps, nil
+	}
+
+	list, err := n.GetServerCapabilities()
+	if err != nil {
+		return nil, err
+	}
+
+	caps := make(map[string]struct{}, len(list))
+	for _, s := range list {
+		caps[s] = struct{}{}
+	}
+
+	c.caps = caps
+	c.cached = true
+	return caps, nil
+}
+
+func (c *capCache) invalidate() {
+	c.mu.Lock()
+	c.caps = nil
+	c.cached = false
+	c.mu.Unlock()
+}
+
+// WithCapabilityNegotiation enables SendNegotiated on a Notifier. It
+// also subscribes to NameOwnerChanged, so that a notification server
+// restarting with a different capability set is picked up.
+func WithCapabilityNegotiation() option {
+	return func(n *notifier) {
+		n.negotiateCaps = true
+	}
+}
+
+func dropHint(note *Notification, key string) (dropped bool) {
+	if _, ok := note.Hints[key]; !ok {
+		return false
+	}
+	delete(note.Hints, key)
+	return true
+}
+
+func dropAnyHint(note *Notification, keys ...string) (dropped bool) {
+	for _, key := range keys {
+		if dropHint(note, key) {
+			dropped = true
+		}
+	}
+	return dropped
+}
+
+// SendNegotiated is like Send, but first strips any feature the
+// server hasn't advertised via GetServerCapabilities, so the
+// Notification degrades gracefully instead of silently failing to
+// render: missing "actions" drops Actions, missing "body-markup"
+// strips <b>/<i>/<u>/<a>/<img> from Body, missing "sound" drops the
+// sound-file/sound-name hints, and missing "body-images" drops the
+// image-data hint (and its legacy aliases).
+//
+// The second return value lists the features that were dropped, for
+// callers that want to log them or otherwise inform the user.
+//
+// Requires WithCapabilityNegotiation to have been passed to New; it
+// returns ErrCapabilityNegotiationDisabled otherwise.
+func (n *notifier) SendNegotiated(note *Notification) (*Handle, []string, error) {
+	if !n.negotiateCaps {
+		return nil, nil, ErrCapabilityNegotiationDisabled
+	}
+
+	caps, err := n.capCache.get(n)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	degraded := *note
+	if note.Hints != nil {
+		degraded.Hints = make(map[string]dbus.Variant, len(note.Hints))
+		for k, v := range note.Hints {
+			degraded.Hints[k] = v
+		}
+	}
+
+	var dropped []string
+
+	if _, ok := caps["actions"]; !ok && len(degraded.Actions) > 0 {
+		degraded.Actions = nil
+		dropped = append(dropped, "actions")
+	}
+
+	if _, ok := caps["body-markup"]; !ok {
+		if stripped := stripBodyMarkup(degraded.Body); stripped != degraded.Body {
+			degraded.Body = stripped
+			dropped = append(dropped, "body-markup")
+		}
+	}
+
+	if _, ok := caps["sound"]; !ok {
+		if dropAnyHint(&degraded, hintSoundFile, hintSoundName) {
+			dropped = append(dropped, "sound")
+		}
+	}
+
+	if _, ok := caps["body-images"]; !ok {
+		if dropAnyHint(&degraded, hintImageData, hintImageDataLegacy, hintIconData) {
+			dropped = append(dropped, "body-images")
+		}
+	}
+
+	h, err := n.Send(&degraded)
+	return h, dropped, err
+}
@@ -0,0 +1,31 @@
+package notify
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStripBodyMarkup(t *testing.T) {
+	cases := []struct {
+		body string
+		want string
+	}{
+		{"plain text", "plain text"},
+		{"<b>bold</b> and <i>italic</i>", "bold and italic"},
+		{`<a href="x">link</a>`, "link"},
+		{`<img src="x"/>`, ""},
+	}
+	for _, tc := range cases {
+		if got := stripBodyMarkup(tc.body); got != tc.want {
+			t.Errorf("stripBodyMarkup(%q) = %q, want %q", tc.body, got, tc.want)
+		}
+	}
+}
+
+func TestSendNegotiatedRequiresOption(t *testing.T) {
+	n := &notifier{}
+	_, _, err := n.SendNegotiated(&Notification{})
+	if !errors.Is(err, ErrCapabilityNegotiationDisabled) {
+		t.Errorf("SendNegotiated without WithCapabilityNegotiation: got err %v, want ErrCapabilityNegotiationDisabled", err)
+	}
+}
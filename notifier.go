@@ -2,6 +2,7 @@ package notify
 
 import (
 	"context"
+	"sync"
 
 	"github.com/godbus/dbus/v5"
 )
@@ -16,16 +17,31 @@ import (
 //
 // Note that Signal delivery currently works by subscribing to
 // all signals, only filtering on signal type. You will see
-// signals for Notifications that other sources have sent.
-// Use Send's return value to filter for relevant Notifications.
+// signals for Notifications that other sources have sent;
+// these are delivered to the Notifier-wide handlers above, since
+// there is no Handle for them to be scoped to.
+//
+// Send returns a Handle, which can be used to register callbacks
+// scoped to just that notification instead of the Notifier-wide
+// handlers.
 //
 // Notifier.Close() should be called before shutting down
-// the underlying connection to ensure a clean shutdown.
+// the underlying connection to ensure a clean shutdown. It cancels
+// any outstanding Handle.Wait calls with ErrNotifierClosed.
+//
+// Subscribe offers a lower-level alternative to WithOnAction/
+// WithOnClosed/Handle: a Subscription delivers Events for notifications
+// matching an ID/action filter over a buffered channel, with a
+// configurable policy for what happens when that channel fills up.
 type Notifier interface {
-	Send(n *Notification) (ID, error)
+	Send(n *Notification) (*Handle, error)
+	SendWithCallbacks(n *Notification, onAction func(actionName string), onClosed func(CloseReason)) (ID, error)
 	Dismiss(id ID) error
 	GetServerCapabilities() ([]string, error)
 	GetServerInfo() (*ServerInfo, error)
+	Subscribe(opts SubscribeOpts) *Subscription
+	Stats() Stats
+	SendNegotiated(n *Notification) (*Handle, []string, error)
 	Close() error
 }
 
@@ -84,6 +100,19 @@ type notifier struct {
 	shutdown context.CancelFunc
 	onClosed NotificationClosedHandler
 	onAction ActionInvokedHandler
+
+	mu       sync.Mutex
+	handles  map[ID]*Handle
+	subs     map[*Subscription]struct{}
+	fallback *Subscription // backs WithOnAction/WithOnClosed; not in subs
+
+	stats struct {
+		dispatched uint64
+		dropped    uint64
+	}
+
+	negotiateCaps bool
+	capCache      capCache
 }
 
 // functional configuration type
@@ -109,12 +138,22 @@ func New(conn *dbus.Conn, opts ...option) (Notifier, error) {
 		signal:   make(chan *dbus.Signal, channelBufferSize),
 		ctx:      ctx,
 		shutdown: cancel,
+		handles:  make(map[ID]*Handle),
+		subs:     make(map[*Subscription]struct{}),
 	}
 
 	for _, val := range opts {
 		val(n)
 	}
 
+	// WithOnAction/WithOnClosed are implemented as a private
+	// Subscription, so they share the bus's buffering/backpressure
+	// machinery instead of being called straight from the signal loop.
+	if n.onAction != nil || n.onClosed != nil {
+		n.fallback = newSubscription(n, SubscribeOpts{Buffer: channelBufferSize})
+		go n.dispatchFallback()
+	}
+
 	// subscribe to notification signals
 	if err := n.conn.AddMatchSignal(
 		dbus.WithMatchObjectPath(dbusObjectPath),
@@ -122,6 +161,18 @@ func New(conn *dbus.Conn, opts ...option) (Notifier, error) {
 	); err != nil {
 		return nil, err
 	}
+
+	if n.negotiateCaps {
+		// invalidate the capability cache if the server is replaced
+		if err := n.conn.AddMatchSignal(
+			dbus.WithMatchInterface("org.freedesktop.DBus"),
+			dbus.WithMatchMember("NameOwnerChanged"),
+			dbus.WithMatchArg(0, dbusNotificationsInterface),
+		); err != nil {
+			return nil, err
+		}
+	}
+
 	n.conn.Signal(n.signal)
 
 	go n.receiveSignals()
@@ -129,26 +180,69 @@ func New(conn *dbus.Conn, opts ...option) (Notifier, error) {
 	return n, nil
 }
 
+// receiveSignals is the bus's single dispatcher goroutine: it decodes
+// each raw dbus signal once, routes it to the matching Handle if any,
+// falling back to WithOnAction/WithOnClosed otherwise, and fans it out
+// to every Subscription registered via Subscribe.
 func (n *notifier) receiveSignals() {
 	for {
 		select {
 		case <-n.ctx.Done():
 			return
 		case signal := <-n.signal:
-			switch signal.Name {
-			case signalNotificationClosed:
+			if n.negotiateCaps && signal.Name == signalNameOwnerChanged {
+				n.capCache.invalidate()
+				continue
+			}
+
+			ev, ok := decodeSignal(signal)
+			if !ok {
+				continue
+			}
+
+			id := ev.eventID()
+
+			n.mu.Lock()
+			h := n.handles[id]
+			if _, isClose := ev.(ClosedEvent); isClose {
+				// reap the handle so it can't leak past its close signal
+				delete(n.handles, id)
+			}
+			n.mu.Unlock()
+
+			switch {
+			case h != nil:
+				switch e := ev.(type) {
+				case ClosedEvent:
+					h.closeWith(e.Reason)
+				case ActionEvent:
+					h.dispatchAction(e.Action)
+				}
+			case n.fallback != nil:
+				n.fallback.deliver(ev)
+			}
+
+			n.publish(ev)
+		}
+	}
+}
+
+// dispatchFallback runs the WithOnAction/WithOnClosed handlers for
+// Events that no Handle claimed.
+func (n *notifier) dispatchFallback() {
+	for {
+		select {
+		case <-n.ctx.Done():
+			return
+		case ev := <-n.fallback.ch:
+			switch e := ev.(type) {
+			case ClosedEvent:
 				if n.onClosed != nil {
-					go n.onClosed(
-						ID(signal.Body[0].(uint32)),
-						CloseReason(signal.Body[1].(uint32)),
-					)
+					go n.onClosed(e.ID, e.Reason)
 				}
-			case signalActionInvoked:
+			case ActionEvent:
 				if n.onAction != nil {
-					go n.onAction(
-						ID(signal.Body[0].(uint32)),
-						signal.Body[1].(string),
-					)
+					go n.onAction(e.ID, e.Action)
 				}
 			}
 		}
@@ -161,6 +255,17 @@ func (n *notifier) Close() error {
 
 	// unsubscribe
 	n.conn.RemoveSignal(n.signal)
+
+	if n.negotiateCaps {
+		if err := n.conn.RemoveMatchSignal(
+			dbus.WithMatchInterface("org.freedesktop.DBus"),
+			dbus.WithMatchMember("NameOwnerChanged"),
+			dbus.WithMatchArg(0, dbusNotificationsInterface),
+		); err != nil {
+			return err
+		}
+	}
+
 	return n.conn.RemoveMatchSignal(
 		dbus.WithMatchObjectPath(dbusObjectPath),
 		dbus.WithMatchInterface(dbusNotificationsInterface),
@@ -168,12 +273,50 @@ func (n *notifier) Close() error {
 }
 
 // Send sends a notification to the notification server.
-// The returned ID can be used as a handle to dismiss the
-// notification and filter for Close/Action events in handlers.
+// The returned Handle can be used to dismiss the notification, wait
+// for it to close, and register Action/Closed callbacks scoped to
+// just this notification, instead of the Notifier-wide
+// WithOnAction/WithOnClosed handlers.
+//
+// Spec: org.freedesktop.Notifications.Notify
+func (n *notifier) Send(note *Notification) (*Handle, error) {
+	id, err := Send(n.conn, note)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Handle{
+		id:       id,
+		notifier: n,
+		closed:   make(chan struct{}),
+	}
+
+	n.mu.Lock()
+	n.handles[id] = h
+	n.mu.Unlock()
+
+	return h, nil
+}
+
+// SendWithCallbacks is a convenience wrapper around Send, for callers
+// who just want a single action/close callback pair scoped to this
+// notification and don't need the rest of Handle's API.
 //
 // Spec: org.freedesktop.Notifications.Notify
-func (n *notifier) Send(note *Notification) (ID, error) {
-	return Send(n.conn, note)
+func (n *notifier) SendWithCallbacks(note *Notification, onAction func(actionName string), onClosed func(CloseReason)) (ID, error) {
+	h, err := n.Send(note)
+	if err != nil {
+		return 0, err
+	}
+
+	if onAction != nil {
+		h.OnAnyAction(onAction)
+	}
+	if onClosed != nil {
+		h.OnClosed(onClosed)
+	}
+
+	return h.ID(), nil
 }
 
 // Dismiss causes a notification to be forcefully closed
@@ -0,0 +1,60 @@
+/*
+Package notifytest provides an in-process fake notification server,
+for driving this repository's client code in tests without a real
+org.freedesktop.Notifications daemon on the bus.
+*/
+package notifytest
+
+import (
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+	notify "github.com/jktr/go-notify"
+	"github.com/jktr/go-notify/server"
+)
+
+// FakeServer is an in-process stand-in for a notification daemon. It
+// records every Notify call and lets a test emit NotificationClosed
+// and ActionInvoked signals on demand, to exercise client code that
+// reacts to them.
+type FakeServer struct {
+	*server.Server
+	backend *server.MemoryBackend
+}
+
+// New starts a FakeServer on conn, a bus connection dedicated to the
+// test -- e.g. one to a throwaway dbus-daemon, as this package's tests
+// use. It requests the well-known org.freedesktop.Notifications name
+// with server.WithReplaceExisting, so on a bus where a real daemon
+// already owns that name the fake competes with it for the name.
+// info and caps are returned verbatim from
+// GetServerInformation/GetCapabilities.
+func New(conn *dbus.Conn, info notify.ServerInfo, caps []string) (*FakeServer, error) {
+	backend := server.NewMemoryBackend(info, caps)
+
+	srv, err := server.NewServer(conn, backend, server.WithReplaceExisting())
+	if err != nil {
+		return nil, fmt.Errorf("notifytest: starting fake server: %w", err)
+	}
+
+	return &FakeServer{Server: srv, backend: backend}, nil
+}
+
+// Notification returns the last Notification sent for id, and whether
+// one was found.
+func (f *FakeServer) Notification(id notify.ID) (notify.Notification, bool) {
+	return f.backend.Notification(id)
+}
+
+// Close emits a NotificationClosed signal for id with reason, as a
+// real server would on timeout or user dismissal. It does not require
+// a prior CloseNotification call from the client.
+func (f *FakeServer) Close(id notify.ID, reason notify.CloseReason) error {
+	return f.EmitNotificationClosed(id, reason)
+}
+
+// InvokeAction emits an ActionInvoked signal for id, as if the user
+// had clicked the action named actionName.
+func (f *FakeServer) InvokeAction(id notify.ID, actionName string) error {
+	return f.EmitActionInvoked(id, actionName)
+}
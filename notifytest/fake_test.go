@@ -0,0 +1,282 @@
+package notifytest_test
+
+import (
+	"bufio"
+	"context"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	notify "github.com/jktr/go-notify"
+	"github.com/jktr/go-notify/notifytest"
+)
+
+// startPrivateBus launches a throwaway dbus-daemon for the duration of
+// the test, so these tests don't depend on a real session bus being
+// available.
+func startPrivateBus(t *testing.T) string {
+	t.Helper()
+
+	cmd := exec.Command("dbus-daemon", "--session", "--print-address", "--nofork")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("starting dbus-daemon: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("dbus-daemon not available: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	scanner := bufio.NewScanner(stdout)
+	if !scanner.Scan() {
+		t.Fatalf("reading dbus-daemon address: %v", scanner.Err())
+	}
+	return strings.TrimSpace(scanner.Text())
+}
+
+func dial(t *testing.T, addr string) *dbus.Conn {
+	t.Helper()
+	conn, err := dbus.Connect(addr)
+	if err != nil {
+		t.Fatalf("connecting to bus: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// TestTypedHints drives the real client's typed hint builders against
+// FakeServer, and checks the server saw exactly what was set.
+func TestTypedHints(t *testing.T) {
+	addr := startPrivateBus(t)
+
+	fake, err := notifytest.New(dial(t, addr),
+		notify.ServerInfo{Name: "fake", Vendor: "jktr", Version: "0", SpecVersion: "1.2"},
+		[]string{"body-markup", "actions", "persistence"})
+	if err != nil {
+		t.Fatalf("notifytest.New: %v", err)
+	}
+
+	notifier, err := notify.New(dial(t, addr))
+	if err != nil {
+		t.Fatalf("notify.New: %v", err)
+	}
+	defer notifier.Close()
+
+	n := (&notify.Notification{AppName: "typed-hints-test", Summary: "s"}).
+		SetCategory(notify.CategoryIMReceived).
+		SetTransient(true).
+		SetResident(true).
+		SetPosition(12, 34)
+
+	h, err := notifier.Send(n)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	sent, ok := fake.Notification(h.ID())
+	if !ok {
+		t.Fatalf("fake server never recorded notification %d", h.ID())
+	}
+
+	if cat, ok := sent.Hints["category"]; !ok || cat.Value() != string(notify.CategoryIMReceived) {
+		t.Errorf("category hint: got %v", cat)
+	}
+	if transient, ok := sent.Hints["transient"]; !ok || transient.Value() != true {
+		t.Errorf("transient hint: got %v", transient)
+	}
+	if resident, ok := sent.Hints["resident"]; !ok || resident.Value() != true {
+		t.Errorf("resident hint: got %v", resident)
+	}
+	if x, ok := sent.Hints["x"]; !ok || x.Value() != int32(12) {
+		t.Errorf("x hint: got %v", x)
+	}
+	if y, ok := sent.Hints["y"]; !ok || y.Value() != int32(34) {
+		t.Errorf("y hint: got %v", y)
+	}
+}
+
+// TestCapabilityNegotiation drives SendNegotiated against a FakeServer
+// that doesn't advertise "actions" or "body-markup", and checks that
+// both are stripped before the notification reaches the server.
+func TestCapabilityNegotiation(t *testing.T) {
+	addr := startPrivateBus(t)
+
+	fake, err := notifytest.New(dial(t, addr),
+		notify.ServerInfo{Name: "fake", Vendor: "jktr", Version: "0", SpecVersion: "1.2"},
+		[]string{"persistence"})
+	if err != nil {
+		t.Fatalf("notifytest.New: %v", err)
+	}
+
+	notifier, err := notify.New(dial(t, addr), notify.WithCapabilityNegotiation())
+	if err != nil {
+		t.Fatalf("notify.New: %v", err)
+	}
+	defer notifier.Close()
+
+	n := &notify.Notification{
+		AppName: "negotiate-test",
+		Summary: "s",
+		Body:    "plain <b>bold</b> text",
+		Actions: []notify.NotificationAction{{Name: "default", Summary: "Open"}},
+	}
+
+	h, dropped, err := notifier.SendNegotiated(n)
+	if err != nil {
+		t.Fatalf("SendNegotiated: %v", err)
+	}
+
+	wantDropped := map[string]bool{"actions": false, "body-markup": false}
+	for _, d := range dropped {
+		wantDropped[d] = true
+	}
+	for feature, seen := range wantDropped {
+		if !seen {
+			t.Errorf("expected %q to be reported dropped, dropped=%v", feature, dropped)
+		}
+	}
+
+	sent, ok := fake.Notification(h.ID())
+	if !ok {
+		t.Fatalf("fake server never recorded notification %d", h.ID())
+	}
+	if len(sent.Actions) != 0 {
+		t.Errorf("actions should have been dropped, got %v", sent.Actions)
+	}
+	if strings.Contains(sent.Body, "<b>") {
+		t.Errorf("body markup should have been stripped, got %q", sent.Body)
+	}
+	// The original Notification passed in must not be mutated.
+	if len(n.Actions) != 1 {
+		t.Errorf("caller's Notification.Actions was mutated: %v", n.Actions)
+	}
+}
+
+// TestPerIDDispatch sends two notifications and checks that each
+// Handle's callbacks only fire for its own ID, never for the other's.
+func TestPerIDDispatch(t *testing.T) {
+	addr := startPrivateBus(t)
+
+	fake, err := notifytest.New(dial(t, addr),
+		notify.ServerInfo{Name: "fake", Vendor: "jktr", Version: "0", SpecVersion: "1.2"},
+		[]string{"actions"})
+	if err != nil {
+		t.Fatalf("notifytest.New: %v", err)
+	}
+
+	notifier, err := notify.New(dial(t, addr))
+	if err != nil {
+		t.Fatalf("notify.New: %v", err)
+	}
+	defer notifier.Close()
+
+	h1, err := notifier.Send(&notify.Notification{AppName: "dispatch-test", Summary: "one"})
+	if err != nil {
+		t.Fatalf("Send 1: %v", err)
+	}
+	h2, err := notifier.Send(&notify.Notification{AppName: "dispatch-test", Summary: "two"})
+	if err != nil {
+		t.Fatalf("Send 2: %v", err)
+	}
+
+	var mu sync.Mutex
+	actions := map[notify.ID][]string{}
+	h1.OnAnyAction(func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		actions[h1.ID()] = append(actions[h1.ID()], name)
+	})
+	h2.OnAnyAction(func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		actions[h2.ID()] = append(actions[h2.ID()], name)
+	})
+
+	if err := fake.InvokeAction(h2.ID(), "default"); err != nil {
+		t.Fatalf("InvokeAction: %v", err)
+	}
+
+	deadline := time.After(2 * time.Second)
+	for {
+		mu.Lock()
+		got := len(actions[h2.ID()])
+		mu.Unlock()
+		if got == 1 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("timed out waiting for ActionInvoked to dispatch")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(actions[h1.ID()]) != 0 {
+		t.Errorf("h1 received actions meant for h2: %v", actions[h1.ID()])
+	}
+	if got := actions[h2.ID()]; len(got) != 1 || got[0] != "default" {
+		t.Errorf("h2 actions: got %v, want [default]", got)
+	}
+}
+
+// TestCloseCleanupRace sends many notifications concurrently and
+// closes each one once while a Wait on its Handle is pending, and
+// checks Wait/Close never race or panic while Handles are being
+// reaped from the Notifier's bookkeeping.
+func TestCloseCleanupRace(t *testing.T) {
+	addr := startPrivateBus(t)
+
+	fake, err := notifytest.New(dial(t, addr),
+		notify.ServerInfo{Name: "fake", Vendor: "jktr", Version: "0", SpecVersion: "1.2"},
+		nil)
+	if err != nil {
+		t.Fatalf("notifytest.New: %v", err)
+	}
+
+	notifier, err := notify.New(dial(t, addr))
+	if err != nil {
+		t.Fatalf("notify.New: %v", err)
+	}
+	defer notifier.Close()
+
+	const n = 50
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			h, err := notifier.Send(&notify.Notification{AppName: "race-test", Summary: "s"})
+			if err != nil {
+				t.Errorf("Send: %v", err)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			var waitWg sync.WaitGroup
+			waitWg.Add(1)
+			go func() {
+				defer waitWg.Done()
+				if _, err := h.Wait(ctx); err != nil {
+					t.Errorf("Wait: %v", err)
+				}
+			}()
+
+			if err := fake.Close(h.ID(), notify.Expired); err != nil {
+				t.Errorf("Close: %v", err)
+			}
+			waitWg.Wait()
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,281 @@
+/*
+Package server lets a Go program act as the notification daemon on a
+session bus, implementing org.freedesktop.Notifications against a
+pluggable Backend.
+
+See also:
+ - https://specifications.freedesktop.org/notification-spec/latest/ar01s09.html
+ - https://github.com/godbus/dbus
+*/
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+	notify "github.com/jktr/go-notify"
+)
+
+const (
+	dbusObjectPath             = "/org/freedesktop/Notifications"
+	dbusNotificationsInterface = "org.freedesktop.Notifications"
+	signalNotificationClosed   = "org.freedesktop.Notifications.NotificationClosed"
+	signalActionInvoked        = "org.freedesktop.Notifications.ActionInvoked"
+)
+
+const introspectXML = `
+<node>
+  <interface name="org.freedesktop.Notifications">
+    <method name="Notify">
+      <arg direction="in" name="app_name" type="s"/>
+      <arg direction="in" name="replaces_id" type="u"/>
+      <arg direction="in" name="app_icon" type="s"/>
+      <arg direction="in" name="summary" type="s"/>
+      <arg direction="in" name="body" type="s"/>
+      <arg direction="in" name="actions" type="as"/>
+      <arg direction="in" name="hints" type="a{sv}"/>
+      <arg direction="in" name="expire_timeout" type="i"/>
+      <arg direction="out" name="id" type="u"/>
+    </method>
+    <method name="CloseNotification">
+      <arg direction="in" name="id" type="u"/>
+    </method>
+    <method name="GetCapabilities">
+      <arg direction="out" name="capabilities" type="as"/>
+    </method>
+    <method name="GetServerInformation">
+      <arg direction="out" name="name" type="s"/>
+      <arg direction="out" name="vendor" type="s"/>
+      <arg direction="out" name="version" type="s"/>
+      <arg direction="out" name="spec_version" type="s"/>
+    </method>
+    <signal name="NotificationClosed">
+      <arg name="id" type="u"/>
+      <arg name="reason" type="u"/>
+    </signal>
+    <signal name="ActionInvoked">
+      <arg name="id" type="u"/>
+      <arg name="action_key" type="s"/>
+    </signal>
+  </interface>` + introspect.IntrospectDataString + `</node>`
+
+// Backend implements the behaviour behind the Notifications interface;
+// Server takes care of the dbus wire format around it.
+type Backend interface {
+	// Notify is called for every incoming Notify method call and
+	// returns the ID to report back to the caller.
+	Notify(ctx context.Context, note notify.Notification) (notify.ID, error)
+	// Close is called for every incoming CloseNotification method
+	// call, with DismissedByCall as the reason.
+	Close(id notify.ID, reason notify.CloseReason) error
+	Capabilities() []string
+	ServerInfo() notify.ServerInfo
+}
+
+// ServerOption overrides certain parts of a Server.
+type ServerOption func(*Server)
+
+// WithReplaceExisting lets this Server take over the well-known
+// org.freedesktop.Notifications name from a previous owner, instead of
+// failing to start if one is already registered.
+func WithReplaceExisting() ServerOption {
+	return func(s *Server) {
+		s.nameFlags |= dbus.NameFlagReplaceExisting
+	}
+}
+
+// Server exports org.freedesktop.Notifications on the session bus,
+// delegating to a Backend for everything except dbus wire handling.
+type Server struct {
+	conn      *dbus.Conn
+	backend   Backend
+	nameFlags dbus.RequestNameFlags
+}
+
+// NewServer exports backend as org.freedesktop.Notifications on conn
+// and requests the well-known bus name for it.
+func NewServer(conn *dbus.Conn, backend Backend, opts ...ServerOption) (*Server, error) {
+	s := &Server{
+		conn:    conn,
+		backend: backend,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if err := conn.Export(s, dbusObjectPath, dbusNotificationsInterface); err != nil {
+		return nil, fmt.Errorf("notify/server: exporting %s: %w", dbusNotificationsInterface, err)
+	}
+	if err := conn.Export(introspect.Introspectable(introspectXML), dbusObjectPath, "org.freedesktop.DBus.Introspectable"); err != nil {
+		return nil, fmt.Errorf("notify/server: exporting introspection: %w", err)
+	}
+
+	reply, err := conn.RequestName(dbusNotificationsInterface, s.nameFlags)
+	if err != nil {
+		return nil, fmt.Errorf("notify/server: requesting name %s: %w", dbusNotificationsInterface, err)
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		return nil, fmt.Errorf("notify/server: name %s is already owned", dbusNotificationsInterface)
+	}
+
+	return s, nil
+}
+
+// Notify implements the Notify dbus method.
+func (s *Server) Notify(appName string, replacesID uint32, appIcon, summary, body string, actions []string, hints map[string]dbus.Variant, expireTimeout int32) (uint32, *dbus.Error) {
+	id, err := s.backend.Notify(context.Background(), noteFromWire(appName, replacesID, appIcon, summary, body, actions, hints, expireTimeout))
+	if err != nil {
+		return 0, dbus.MakeFailedError(err)
+	}
+	return uint32(id), nil
+}
+
+// CloseNotification implements the CloseNotification dbus method.
+func (s *Server) CloseNotification(id uint32) *dbus.Error {
+	if err := s.backend.Close(notify.ID(id), notify.DismissedByCall); err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	if err := s.EmitNotificationClosed(notify.ID(id), notify.DismissedByCall); err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	return nil
+}
+
+// GetCapabilities implements the GetCapabilities dbus method.
+func (s *Server) GetCapabilities() ([]string, *dbus.Error) {
+	return s.backend.Capabilities(), nil
+}
+
+// GetServerInformation implements the GetServerInformation dbus method.
+func (s *Server) GetServerInformation() (string, string, string, string, *dbus.Error) {
+	info := s.backend.ServerInfo()
+	return info.Name, info.Vendor, info.Version, info.SpecVersion, nil
+}
+
+// EmitActionInvoked emits an ActionInvoked signal for id, as if the
+// user had invoked actionName.
+func (s *Server) EmitActionInvoked(id notify.ID, actionName string) error {
+	return s.conn.Emit(dbusObjectPath, signalActionInvoked, uint32(id), actionName)
+}
+
+// EmitNotificationClosed emits a NotificationClosed signal for id.
+// Backends must call this themselves on every path that closes a
+// notification (expiry, CloseNotification is handled by Server), since
+// Server has no notion of notification lifetime beyond Notify/Close.
+func (s *Server) EmitNotificationClosed(id notify.ID, reason notify.CloseReason) error {
+	return s.conn.Emit(dbusObjectPath, signalNotificationClosed, uint32(id), uint32(reason))
+}
+
+func noteFromWire(appName string, replacesID uint32, appIcon, summary, body string, actionPairs []string, hints map[string]dbus.Variant, expireTimeout int32) notify.Notification {
+	var actions []notify.NotificationAction
+	for i := 0; i+1 < len(actionPairs); i += 2 {
+		actions = append(actions, notify.NotificationAction{
+			Name:    actionPairs[i],
+			Summary: actionPairs[i+1],
+		})
+	}
+
+	expire, timeout := expiryFromWire(expireTimeout)
+
+	return notify.Notification{
+		AppName:    appName,
+		ReplacesID: notify.ID(replacesID),
+		AppIcon:    appIcon,
+		Summary:    summary,
+		Body:       body,
+		Actions:    actions,
+		Hints:      hints,
+		Expire:     expire,
+		Timeout:    timeout,
+	}
+}
+
+func expiryFromWire(expireTimeout int32) (notify.Expiry, time.Duration) {
+	switch {
+	case expireTimeout < 0:
+		return notify.Server, 0
+	case expireTimeout == 0:
+		return notify.Never, 0
+	default:
+		return notify.Timeout, time.Duration(expireTimeout) * time.Millisecond
+	}
+}
+
+// idGenerator hands out monotonically increasing IDs, skipping the
+// zero value, which the spec reserves.
+type idGenerator struct {
+	next uint32
+}
+
+func (g *idGenerator) Next() notify.ID {
+	id := atomic.AddUint32(&g.next, 1)
+	if id == 0 {
+		id = atomic.AddUint32(&g.next, 1)
+	}
+	return notify.ID(id)
+}
+
+// MemoryBackend is a minimal in-memory reference Backend: it assigns
+// IDs, keeps the last Notification sent for each around until a
+// CloseNotification call for that ID removes it, and reports
+// caller-configured capabilities and server info. It never closes
+// notifications itself (e.g. on timeout); callers drive that via
+// Server.EmitNotificationClosed.
+type MemoryBackend struct {
+	Info notify.ServerInfo
+	Caps []string
+
+	ids   idGenerator
+	mu    sync.Mutex
+	notes map[notify.ID]notify.Notification
+}
+
+// NewMemoryBackend creates a MemoryBackend reporting info and caps.
+func NewMemoryBackend(info notify.ServerInfo, caps []string) *MemoryBackend {
+	return &MemoryBackend{
+		Info:  info,
+		Caps:  caps,
+		notes: make(map[notify.ID]notify.Notification),
+	}
+}
+
+func (b *MemoryBackend) Notify(ctx context.Context, note notify.Notification) (notify.ID, error) {
+	id := note.ReplacesID
+	if id == 0 {
+		id = b.ids.Next()
+	}
+
+	b.mu.Lock()
+	b.notes[id] = note
+	b.mu.Unlock()
+
+	return id, nil
+}
+
+func (b *MemoryBackend) Close(id notify.ID, reason notify.CloseReason) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.notes[id]; !ok {
+		return fmt.Errorf("notify/server: unknown notification id %d", id)
+	}
+	delete(b.notes, id)
+	return nil
+}
+
+func (b *MemoryBackend) Capabilities() []string        { return b.Caps }
+func (b *MemoryBackend) ServerInfo() notify.ServerInfo { return b.Info }
+
+// Notification returns the last Notification sent for id.
+func (b *MemoryBackend) Notification(id notify.ID) (notify.Notification, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n, ok := b.notes[id]
+	return n, ok
+}
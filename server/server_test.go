@@ -0,0 +1,152 @@
+package server_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	notify "github.com/jktr/go-notify"
+	"github.com/jktr/go-notify/server"
+)
+
+// startPrivateBus launches a throwaway dbus-daemon for the duration of
+// the test, so the client/server round-trip below doesn't depend on a
+// real session bus being available.
+func startPrivateBus(t *testing.T) string {
+	t.Helper()
+
+	cmd := exec.Command("dbus-daemon", "--session", "--print-address", "--nofork")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("starting dbus-daemon: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("dbus-daemon not available: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	scanner := bufio.NewScanner(stdout)
+	if !scanner.Scan() {
+		t.Fatalf("reading dbus-daemon address: %v", scanner.Err())
+	}
+	return strings.TrimSpace(scanner.Text())
+}
+
+// TestRoundTrip drives this repository's client (notify.Notifier) against
+// this repository's server (server.Server backed by a MemoryBackend) over
+// a real dbus connection, proving hints, actions, and close reasons
+// survive the wire round-trip end to end.
+func TestRoundTrip(t *testing.T) {
+	addr := startPrivateBus(t)
+
+	srvConn, err := dbus.Connect(addr)
+	if err != nil {
+		t.Fatalf("connecting server to bus: %v", err)
+	}
+	defer srvConn.Close()
+
+	backend := server.NewMemoryBackend(
+		notify.ServerInfo{Name: "go-notify test server", Vendor: "jktr", Version: "0", SpecVersion: "1.2"},
+		[]string{"body-markup", "actions", "persistence"},
+	)
+	srv, err := server.NewServer(srvConn, backend)
+	if err != nil {
+		t.Fatalf("starting server: %v", err)
+	}
+
+	cliConn, err := dbus.Connect(addr)
+	if err != nil {
+		t.Fatalf("connecting client to bus: %v", err)
+	}
+	defer cliConn.Close()
+
+	notifier, err := notify.New(cliConn)
+	if err != nil {
+		t.Fatalf("notify.New: %v", err)
+	}
+	defer notifier.Close()
+
+	cases := []struct {
+		name   string
+		note   *notify.Notification
+		reason notify.CloseReason
+	}{
+		{
+			name: "hints and actions, expired",
+			note: (&notify.Notification{
+				AppName: "roundtrip-test",
+				Summary: "summary",
+				Body:    "body",
+				Actions: []notify.NotificationAction{
+					{Name: "default", Summary: "Open"},
+					{Name: "cancel", Summary: "Cancel"},
+				},
+			}).SetCategory(notify.CategoryEmailArrived).SetUrgency(notify.Critical).SetTransient(true),
+			reason: notify.Expired,
+		},
+		{
+			name: "no hints, dismissed by user",
+			note: &notify.Notification{
+				AppName: "roundtrip-test",
+				Summary: "plain",
+			},
+			reason: notify.DismissedByUser,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h, err := notifier.Send(tc.note)
+			if err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+
+			sent, ok := backend.Notification(h.ID())
+			if !ok {
+				t.Fatalf("server never recorded notification %d", h.ID())
+			}
+			if sent.Summary != tc.note.Summary || sent.Body != tc.note.Body {
+				t.Fatalf("summary/body mismatch: got %+v, want %+v", sent, tc.note)
+			}
+			if len(sent.Actions) != len(tc.note.Actions) {
+				t.Fatalf("actions mismatch: got %v, want %v", sent.Actions, tc.note.Actions)
+			}
+			for i, act := range tc.note.Actions {
+				if sent.Actions[i] != act {
+					t.Fatalf("action %d mismatch: got %+v, want %+v", i, sent.Actions[i], act)
+				}
+			}
+			for k, want := range tc.note.Hints {
+				got, ok := sent.Hints[k]
+				// Wire round-tripping decodes named types (e.g. Urgency)
+				// back as their dbus-native underlying type, so compare
+				// string representations rather than the Variants.
+				if !ok || fmt.Sprint(got.Value()) != fmt.Sprint(want.Value()) {
+					t.Fatalf("hint %q mismatch: got %v, want %v", k, got.Value(), want.Value())
+				}
+			}
+
+			if err := srv.EmitNotificationClosed(h.ID(), tc.reason); err != nil {
+				t.Fatalf("EmitNotificationClosed: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			reason, err := h.Wait(ctx)
+			if err != nil {
+				t.Fatalf("Wait: %v", err)
+			}
+			if reason != tc.reason {
+				t.Fatalf("close reason: got %v, want %v", reason, tc.reason)
+			}
+		})
+	}
+}